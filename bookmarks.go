@@ -0,0 +1,235 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	. "strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
+)
+
+// recentDirsCap bounds how many recently-visited directories we remember, so
+// the MRU list in the jump prompt stays relevant instead of growing forever.
+const recentDirsCap = 50
+
+// bookmarks maps a single-character mnemonic (set with keyBookmark) to a
+// saved path, and recents is a most-recently-visited-first list of
+// directories. Both are package-level, like settings and previewCache, and
+// persisted under ~/.local/share/llama so they survive between runs.
+var (
+	bookmarks map[string]string
+	recents   []string
+)
+
+// dataDir is ~/.local/share/llama, created on first use.
+func dataDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".local", "share", "llama")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// loadBookmarks reads ~/.local/share/llama/bookmarks into the package-level
+// bookmarks map. A missing or unreadable file just means no bookmarks yet.
+func loadBookmarks() {
+	bookmarks = make(map[string]string)
+	dir, err := dataDir()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "bookmarks"))
+	if err != nil {
+		return
+	}
+	for _, line := range Split(string(data), "\n") {
+		name, path, ok := Cut(line, "\t")
+		if !ok || name == "" || path == "" {
+			continue
+		}
+		bookmarks[name] = path
+	}
+}
+
+// saveBookmark records name -> path under the current directory and
+// persists the whole map.
+func saveBookmark(name, path string) {
+	if bookmarks == nil {
+		bookmarks = make(map[string]string)
+	}
+	bookmarks[name] = path
+
+	dir, err := dataDir()
+	if err != nil {
+		return
+	}
+	var b Builder
+	for name, path := range bookmarks {
+		b.WriteString(name)
+		b.WriteString("\t")
+		b.WriteString(path)
+		b.WriteString("\n")
+	}
+	_ = os.WriteFile(filepath.Join(dir, "bookmarks"), []byte(b.String()), 0o644)
+}
+
+// loadRecentDirs reads ~/.local/share/llama/recent into the package-level
+// recents slice, most-recently-visited first.
+func loadRecentDirs() {
+	dir, err := dataDir()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "recent"))
+	if err != nil {
+		return
+	}
+	for _, line := range Split(TrimSpace(string(data)), "\n") {
+		if line != "" {
+			recents = append(recents, line)
+		}
+	}
+}
+
+// recordRecentDir moves path to the front of recents (adding it if it's
+// new), drops it from wherever else it appeared, caps the list at
+// recentDirsCap, and persists it. Called whenever a pane navigates to a new
+// directory.
+func recordRecentDir(path string) {
+	filtered := make([]string, 0, len(recents))
+	for _, p := range recents {
+		if p != path {
+			filtered = append(filtered, p)
+		}
+	}
+	recents = append([]string{path}, filtered...)
+	if len(recents) > recentDirsCap {
+		recents = recents[:recentDirsCap]
+	}
+
+	dir, err := dataDir()
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, "recent"), []byte(Join(recents, "\n")+"\n"), 0o644)
+}
+
+// jumpEntry is one row in the keyJumpPrompt ("z") fuzzy prompt: a bookmark
+// or a recently-visited directory.
+type jumpEntry struct {
+	display string // What's matched against and shown.
+	path    string
+}
+
+// jumpEntries lists bookmarks (sorted by mnemonic, for a stable order)
+// followed by recents, for the fuzzy prompt to search over.
+func jumpEntries() []jumpEntry {
+	names := make([]string, 0, len(bookmarks))
+	for name := range bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]jumpEntry, 0, len(names)+len(recents))
+	for _, name := range names {
+		path := bookmarks[name]
+		entries = append(entries, jumpEntry{display: name + "  " + path, path: path})
+	}
+	for _, path := range recents {
+		entries = append(entries, jumpEntry{display: path, path: path})
+	}
+	return entries
+}
+
+// startJumpPrompt opens the fuzzy prompt over every bookmark and recently
+// visited directory.
+func (m *model) startJumpPrompt() {
+	m.prompt = promptJump
+	m.jumpEntriesCache = jumpEntries()
+	m.jumpInput = textinput.New()
+	m.jumpInput.Prompt = "Jump to: "
+	m.jumpInput.Focus()
+	m.jumpSelected = 0
+	m.refreshJumpMatches()
+}
+
+// refreshJumpMatches re-runs the fuzzy filter against the current prompt
+// text; an empty query matches every entry, in jumpEntries order.
+func (m *model) refreshJumpMatches() {
+	query := m.jumpInput.Value()
+	if query == "" {
+		m.jumpMatches = m.jumpMatches[:0]
+		for i, entry := range m.jumpEntriesCache {
+			m.jumpMatches = append(m.jumpMatches, fuzzy.Match{Str: entry.display, Index: i})
+		}
+		m.jumpSelected = 0
+		return
+	}
+
+	displays := make([]string, len(m.jumpEntriesCache))
+	for i, entry := range m.jumpEntriesCache {
+		displays[i] = entry.display
+	}
+	m.jumpMatches = fuzzy.Find(query, displays)
+	if m.jumpSelected >= len(m.jumpMatches) {
+		m.jumpSelected = 0
+	}
+}
+
+// updateJumpPrompt handles a key while the fuzzy jump prompt is active.
+func (m *model) updateJumpPrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		if m.jumpSelected < len(m.jumpMatches) {
+			path := m.jumpEntriesCache[m.jumpMatches[m.jumpSelected].Index].path
+			m.jumpTo(path)
+		}
+		m.prompt = promptNone
+	case "esc":
+		m.prompt = promptNone
+	case "up", "ctrl+k":
+		if m.jumpSelected > 0 {
+			m.jumpSelected--
+		}
+	case "down", "ctrl+j":
+		if m.jumpSelected < len(m.jumpMatches)-1 {
+			m.jumpSelected++
+		}
+	default:
+		var cmd tea.Cmd
+		m.jumpInput, cmd = m.jumpInput.Update(msg)
+		m.refreshJumpMatches()
+		return cmd
+	}
+	return nil
+}
+
+// jumpTo switches the focused pane to path, restoring its saved cursor
+// position the same way ordinary navigation does.
+func (m *model) jumpTo(path string) {
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		return
+	}
+	m.focusedPane().enterPath(path)
+}
+
+// jumpView renders the jump prompt's text input followed by its matches,
+// with the selected one highlighted.
+func (m *model) jumpView() string {
+	lines := []string{m.jumpInput.View()}
+	for i, match := range m.jumpMatches {
+		line := "  " + match.Str
+		if i == m.jumpSelected {
+			line = cursor.Render("> " + match.Str)
+		}
+		lines = append(lines, line)
+	}
+	return Join(lines, "\n")
+}
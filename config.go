@@ -0,0 +1,165 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/key"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the schema of ~/.config/llama/config.yaml. Every field is
+// optional; anything left unset keeps today's built-in default, so users can
+// override just the one or two bindings they care about.
+type config struct {
+	Keys     keysConfig     `yaml:"keys"`
+	Settings settingsConfig `yaml:"settings"`
+}
+
+// keysConfig maps config file key names to the key strings bubbles/key
+// expects (e.g. "ctrl+w", "shift+up").
+type keysConfig struct {
+	Up          string `yaml:"up"`
+	Down        string `yaml:"down"`
+	Left        string `yaml:"left"`
+	Right       string `yaml:"right"`
+	Top         string `yaml:"top"`
+	Bottom      string `yaml:"bottom"`
+	Leftmost    string `yaml:"leftmost"`
+	Rightmost   string `yaml:"rightmost"`
+	VimUp       string `yaml:"vim_up"`
+	VimDown     string `yaml:"vim_down"`
+	VimLeft     string `yaml:"vim_left"`
+	VimRight    string `yaml:"vim_right"`
+	VimTop      string `yaml:"vim_top"`
+	VimBottom   string `yaml:"vim_bottom"`
+	Quit        string `yaml:"quit"`
+	ForceQuit   string `yaml:"force_quit"`
+	Open        string `yaml:"open"`
+	Back        string `yaml:"back"`
+	Search      string `yaml:"search"`
+	Preview     string `yaml:"preview"`
+	Select      string `yaml:"select"`
+	Delete      string `yaml:"delete"`
+	Rename      string `yaml:"rename"`
+	Yank        string `yaml:"yank"`
+	Cut         string `yaml:"cut"`
+	Paste       string `yaml:"paste"`
+	Mkdir       string `yaml:"mkdir"`
+	SplitRight  string `yaml:"split_right"`
+	SplitBelow  string `yaml:"split_below"`
+	ClosePane   string `yaml:"close_pane"`
+	CycleFocus  string `yaml:"cycle_focus"`
+	CycleFocusR string `yaml:"cycle_focus_reverse"`
+	Widen       string `yaml:"widen"`
+	Narrow      string `yaml:"narrow"`
+	Taller      string `yaml:"taller"`
+	Shorter     string `yaml:"shorter"`
+
+	ToggleHidden string `yaml:"toggle_hidden"`
+	Filter       string `yaml:"filter"`
+	SortCycle    string `yaml:"sort_cycle"`
+	SortReverse  string `yaml:"sort_reverse"`
+
+	Bookmark   string `yaml:"bookmark"`
+	JumpMark   string `yaml:"jump_mark"`
+	JumpPrompt string `yaml:"jump_prompt"`
+}
+
+// settingsConfig is the non-keymap half of the config file.
+type settingsConfig struct {
+	Editor    string `yaml:"editor"`
+	Previewer string `yaml:"previewer"`
+	Hidden    bool   `yaml:"hidden"`
+	Icons     bool   `yaml:"icons"`
+	Columns   string `yaml:"columns"`
+}
+
+// settings holds the effective, resolved settings, threaded into every pane
+// newPane creates.
+var settings settingsConfig
+
+// loadConfig reads ~/.config/llama/config.yaml. A missing or unreadable file
+// isn't an error: it just means every key and setting keeps its default.
+func loadConfig() config {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return config{}
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".config", "llama", "config.yaml"))
+	if err != nil {
+		return config{}
+	}
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return config{}
+	}
+	return cfg
+}
+
+// buildKeys wires the parsed keymap into the package-level key.Binding vars,
+// falling back to today's defaults for anything left unset.
+func buildKeys(cfg keysConfig) {
+	keyUp = bindKey(cfg.Up, "up")
+	keyDown = bindKey(cfg.Down, "down")
+	keyLeft = bindKey(cfg.Left, "left")
+	keyRight = bindKey(cfg.Right, "right")
+	keyTop = bindKey(cfg.Top, "shift+up")
+	keyBottom = bindKey(cfg.Bottom, "shift+down")
+	keyLeftmost = bindKey(cfg.Leftmost, "shift+left")
+	keyRightmost = bindKey(cfg.Rightmost, "shift+right")
+	keyVimUp = bindKey(cfg.VimUp, "k")
+	keyVimDown = bindKey(cfg.VimDown, "j")
+	keyVimLeft = bindKey(cfg.VimLeft, "h")
+	keyVimRight = bindKey(cfg.VimRight, "l")
+	keyVimTop = bindKey(cfg.VimTop, "g")
+	keyVimBottom = bindKey(cfg.VimBottom, "G")
+	keyQuit = bindKey(cfg.Quit, "esc")
+	keyForceQuit = bindKey(cfg.ForceQuit, "ctrl+c")
+	keyOpen = bindKey(cfg.Open, "enter")
+	keyBack = bindKey(cfg.Back, "backspace")
+	keySearch = bindKey(cfg.Search, "/")
+	keyPreview = bindKey(cfg.Preview, lookup([]string{"LLAMA_PREVIEW_KEY"}, "ctrl+p"))
+	keySelect = bindKey(cfg.Select, " ")
+	keyDelete = bindKey(cfg.Delete, "d")
+	keyRename = bindKey(cfg.Rename, "r")
+	keyYank = bindKey(cfg.Yank, "y")
+	keyCut = bindKey(cfg.Cut, "x")
+	keyPaste = bindKey(cfg.Paste, "p")
+	keyMkdir = bindKey(cfg.Mkdir, "n")
+	keySplitRight = bindKey(cfg.SplitRight, "ctrl+w")
+	keySplitBelow = bindKey(cfg.SplitBelow, "ctrl+e")
+	keyClosePane = bindKey(cfg.ClosePane, "ctrl+q")
+	keyCycleFocus = bindKey(cfg.CycleFocus, "tab")
+	keyCycleFocusR = bindKey(cfg.CycleFocusR, "shift+tab")
+	keyWiden = bindKey(cfg.Widen, "ctrl+right")
+	keyNarrow = bindKey(cfg.Narrow, "ctrl+left")
+	keyTaller = bindKey(cfg.Taller, "ctrl+down")
+	keyShorter = bindKey(cfg.Shorter, "ctrl+up")
+	keyToggleHidden = bindKey(cfg.ToggleHidden, ".")
+	keyFilter = bindKey(cfg.Filter, "f")
+	keySortCycle = bindKey(cfg.SortCycle, "s")
+	keySortReverse = bindKey(cfg.SortReverse, "S")
+	keyBookmark = bindKey(cfg.Bookmark, "m")
+	keyJumpMark = bindKey(cfg.JumpMark, "'")
+	keyJumpPrompt = bindKey(cfg.JumpPrompt, "z")
+}
+
+func bindKey(configured, def string) key.Binding {
+	if configured == "" {
+		configured = def
+	}
+	return key.NewBinding(key.WithKeys(configured))
+}
+
+// buildSettings resolves settingsConfig into the package-level settings,
+// falling back to environment variables and today's defaults.
+func buildSettings(cfg settingsConfig) {
+	settings = cfg
+	if settings.Editor == "" {
+		settings.Editor = lookup([]string{"LLAMA_EDITOR", "EDITOR"}, "less")
+	}
+	if settings.Columns == "" {
+		settings.Columns = "auto"
+	}
+}
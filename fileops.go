@@ -0,0 +1,297 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardMode tracks what a yank/cut is going to do when pasted.
+type clipboardMode int
+
+const (
+	clipboardNone clipboardMode = iota
+	clipboardCopy
+	clipboardCut
+)
+
+// promptKind is which modal overlay, if any, is currently capturing all key
+// input. Only one can be active at a time.
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptConfirmDelete
+	promptRename
+	promptMkdir
+	promptOverwrite
+	promptPasteAs
+	promptJump
+)
+
+// startDelete asks for confirmation before removing the focused pane's
+// selection (or the file under the cursor, if nothing is selected).
+func (m *model) startDelete() {
+	targets := m.focusedPane().selectedOrCurrent()
+	if len(targets) == 0 {
+		return
+	}
+	m.prompt = promptConfirmDelete
+	m.promptTarget = fmt.Sprintf("Delete %d item(s)? (y/n)", len(targets))
+}
+
+// startRename opens a text input overlay pre-filled with the current file's
+// name.
+func (m *model) startRename() {
+	filePath, ok := m.focusedPane().filePath()
+	if !ok {
+		return
+	}
+	m.prompt = promptRename
+	m.promptTarget = filePath
+	m.promptInput = textinput.New()
+	m.promptInput.Prompt = "Rename to: "
+	m.promptInput.SetValue(filepath.Base(filePath))
+	m.promptInput.Focus()
+}
+
+// startMkdir opens a text input overlay for a new directory name.
+func (m *model) startMkdir() {
+	m.prompt = promptMkdir
+	m.promptTarget = m.focusedPane().path
+	m.promptInput = textinput.New()
+	m.promptInput.Prompt = "New directory: "
+	m.promptInput.Focus()
+}
+
+// startPaste copies or moves the clipboard into the focused pane's
+// directory, queuing conflicts to be resolved one at a time.
+func (m *model) startPaste() {
+	if m.clipboardMode == clipboardNone || len(m.clipboard) == 0 {
+		return
+	}
+	m.pasteQueue = append([]string{}, m.clipboard...)
+	m.pasteDestDir = m.focusedPane().path
+	m.pasteRenameTo = ""
+	m.advancePaste()
+}
+
+// advancePaste processes the paste queue until it's empty or a collision
+// needs the user to pick overwrite/skip/rename. pasteRenameTo, if set,
+// overrides the destination basename for the item at the front of the
+// queue, so that a "paste as" choice goes through the same collision check
+// as everything else instead of clobbering blindly.
+func (m *model) advancePaste() {
+	for len(m.pasteQueue) > 0 {
+		src := m.pasteQueue[0]
+		name := filepath.Base(src)
+		if m.pasteRenameTo != "" {
+			name = m.pasteRenameTo
+		}
+		dest := filepath.Join(m.pasteDestDir, name)
+		if _, err := os.Stat(dest); err == nil {
+			m.prompt = promptOverwrite
+			m.promptTarget = dest
+			return
+		}
+		m.pasteQueue = m.pasteQueue[1:]
+		m.pasteRenameTo = ""
+		_ = m.copyOrMove(src, dest)
+	}
+	m.finishPaste()
+}
+
+func (m *model) finishPaste() {
+	dirs := []string{m.pasteDestDir}
+	if m.clipboardMode == clipboardCut {
+		for _, src := range m.clipboard {
+			dirs = append(dirs, filepath.Dir(src))
+		}
+		m.clipboardMode = clipboardNone
+		m.clipboard = nil
+	}
+	m.refreshPanesShowing(dirs...)
+}
+
+// refreshPanesShowing re-lists every pane currently browsing one of dirs.
+// Mutating ops only touch the panes they're invoked from, so any other pane
+// browsing an affected directory would otherwise keep stale fs.DirEntry
+// values around until the user happened to navigate it again.
+func (m *model) refreshPanesShowing(dirs ...string) {
+	for _, p := range m.allPanes() {
+		for _, dir := range dirs {
+			if p.path == dir {
+				p.list()
+				break
+			}
+		}
+	}
+}
+
+// copyOrMove applies the active clipboard mode to a single src/dest pair.
+func (m *model) copyOrMove(src, dest string) error {
+	if m.clipboardMode == clipboardCut {
+		if err := os.Rename(src, dest); err == nil {
+			return nil
+		}
+		// Cross-device rename fails; fall back to copy then remove.
+		if err := copyRecursive(src, dest); err != nil {
+			return err
+		}
+		return os.RemoveAll(src)
+	}
+	return copyRecursive(src, dest)
+}
+
+// copyRecursive copies a file or, for directories, its entire tree.
+func copyRecursive(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return copyFile(src, dest, info.Mode())
+	}
+	if err := os.MkdirAll(dest, info.Mode()); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := copyRecursive(filepath.Join(src, entry.Name()), filepath.Join(dest, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dest string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// updatePrompt handles a key while a modal overlay is active, and returns
+// once the overlay is resolved (or still needs more input).
+func (m *model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch m.prompt {
+	case promptJump:
+		return m.updateJumpPrompt(msg)
+
+	case promptConfirmDelete:
+		switch msg.String() {
+		case "y", "Y":
+			targets := m.focusedPane().selectedOrCurrent()
+			var dirs []string
+			for _, target := range targets {
+				_ = os.RemoveAll(target)
+				dirs = append(dirs, filepath.Dir(target))
+			}
+			m.focusedPane().selection = make(map[string]struct{})
+			m.refreshPanesShowing(dirs...)
+			m.prompt = promptNone
+		case "n", "N", "esc":
+			m.prompt = promptNone
+		}
+		return nil
+
+	case promptOverwrite:
+		src := m.pasteQueue[0]
+		dest := m.promptTarget
+		switch msg.String() {
+		case "o":
+			_ = os.RemoveAll(dest)
+			m.pasteQueue = m.pasteQueue[1:]
+			m.pasteRenameTo = ""
+			_ = m.copyOrMove(src, dest)
+			m.prompt = promptNone
+			m.advancePaste()
+		case "s":
+			m.pasteQueue = m.pasteQueue[1:]
+			m.pasteRenameTo = ""
+			m.prompt = promptNone
+			m.advancePaste()
+		case "r":
+			m.prompt = promptPasteAs
+			m.promptTarget = dest
+			m.promptInput = textinput.New()
+			m.promptInput.Prompt = "Paste as: "
+			m.promptInput.SetValue(filepath.Base(dest))
+			m.promptInput.Focus()
+		case "esc":
+			m.pasteQueue = nil
+			m.pasteRenameTo = ""
+			m.prompt = promptNone
+		}
+		return nil
+
+	case promptRename, promptMkdir:
+		switch msg.String() {
+		case "enter":
+			name := m.promptInput.Value()
+			switch m.prompt {
+			case promptRename:
+				newPath := filepath.Join(filepath.Dir(m.promptTarget), name)
+				if newPath != m.promptTarget {
+					if _, err := os.Stat(newPath); err == nil {
+						// Refuse to clobber an existing file; leave the
+						// prompt open so the user can pick another name.
+						return nil
+					}
+				}
+				_ = os.Rename(m.promptTarget, newPath)
+				m.refreshPanesShowing(filepath.Dir(m.promptTarget))
+			case promptMkdir:
+				_ = os.MkdirAll(filepath.Join(m.promptTarget, name), 0o755)
+				m.refreshPanesShowing(m.promptTarget)
+			}
+			m.prompt = promptNone
+		case "esc":
+			m.prompt = promptNone
+		default:
+			var cmd tea.Cmd
+			m.promptInput, cmd = m.promptInput.Update(msg)
+			return cmd
+		}
+		return nil
+
+	case promptPasteAs:
+		switch msg.String() {
+		case "enter":
+			// Let advancePaste re-run the collision check against the
+			// chosen name instead of copying over it unconditionally; if
+			// it also already exists, this re-opens promptOverwrite.
+			m.pasteRenameTo = m.promptInput.Value()
+			m.prompt = promptNone
+			m.advancePaste()
+		case "esc":
+			m.pasteQueue = nil
+			m.pasteRenameTo = ""
+			m.prompt = promptNone
+		default:
+			var cmd tea.Cmd
+			m.promptInput, cmd = m.promptInput.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	. "strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gitState classifies a directory entry's status relative to its git
+// worktree, as shown by `git status --porcelain=v1`.
+type gitState int
+
+const (
+	gitNone gitState = iota
+	gitIgnored
+	gitUntracked
+	gitModified
+	gitStaged
+	gitConflict
+)
+
+// gitStatusStyle returns the lipgloss style to render a name with, given its
+// gitState. gitNone renders as-is.
+func gitStatusStyle(state gitState) lipgloss.Style {
+	switch state {
+	case gitUntracked:
+		return gitUntrackedStyle
+	case gitModified:
+		return gitModifiedStyle
+	case gitStaged:
+		return gitStagedStyle
+	case gitIgnored:
+		return gitIgnoredStyle
+	case gitConflict:
+		return gitConflictStyle
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// refreshGitStatus recomputes p.gitStatus for p.path. It's a no-op outside a
+// git worktree, so panes browsing non-repo directories pay nothing.
+func (p *pane) refreshGitStatus() {
+	p.gitStatus = nil
+	if findGitRoot(p.path) == "" {
+		return
+	}
+
+	out, err := exec.Command("git", "-C", p.path, "status", "--porcelain=v1", "-z", "--ignored").Output()
+	if err != nil {
+		return
+	}
+	p.gitStatus = parseGitStatus(string(out))
+}
+
+// findGitRoot walks up from dir looking for a .git entry, returning the
+// containing directory, or "" if dir isn't inside a worktree.
+func findGitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// parseGitStatus parses the NUL-delimited output of `git status --porcelain=v1
+// -z --ignored` run with -C pointed at a pane's path, into a map keyed by the
+// first path component below that path (so a change anywhere under a
+// subdirectory colors the subdirectory entry too). Renamed/copied entries
+// carry an extra NUL-delimited field for the original path, which we skip.
+func parseGitStatus(out string) map[string]gitState {
+	result := make(map[string]gitState)
+	fields := Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		entry := fields[i]
+		if len(entry) < 4 {
+			continue
+		}
+		code := entry[:2]
+		filePath := entry[3:]
+		if code[0] == 'R' || code[0] == 'C' {
+			i++ // Skip the original path field that follows a rename/copy.
+		}
+
+		name := filePath
+		if idx := IndexByte(filePath, '/'); idx >= 0 {
+			name = filePath[:idx]
+		}
+		state := classifyGitCode(code)
+		if existing, ok := result[name]; !ok || gitSeverity(state) > gitSeverity(existing) {
+			result[name] = state
+		}
+	}
+	return result
+}
+
+// classifyGitCode maps a two-letter porcelain status code to a gitState.
+func classifyGitCode(code string) gitState {
+	x, y := code[0], code[1]
+	switch {
+	case code == "??":
+		return gitUntracked
+	case code == "!!":
+		return gitIgnored
+	case x == 'U' || y == 'U' || code == "AA" || code == "DD":
+		return gitConflict
+	case y != ' ':
+		return gitModified
+	case x != ' ':
+		return gitStaged
+	default:
+		return gitNone
+	}
+}
+
+// gitSeverity ranks states so that, when several entries under a directory
+// disagree, the directory is colored by the most attention-worthy one.
+func gitSeverity(state gitState) int {
+	switch state {
+	case gitConflict:
+		return 5
+	case gitStaged:
+		return 4
+	case gitModified:
+		return 3
+	case gitUntracked:
+		return 2
+	case gitIgnored:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassifyGitCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want gitState
+	}{
+		{"??", gitUntracked},
+		{"!!", gitIgnored},
+		{"UU", gitConflict},
+		{"AA", gitConflict},
+		{"DD", gitConflict},
+		{"AU", gitConflict},
+		{"UA", gitConflict},
+		{" M", gitModified},
+		{"MM", gitModified},
+		{"M ", gitStaged},
+		{"A ", gitStaged},
+		{"  ", gitNone},
+	}
+	for _, tt := range tests {
+		if got := classifyGitCode(tt.code); got != tt.want {
+			t.Errorf("classifyGitCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestParseGitStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[string]gitState
+	}{
+		{
+			name: "empty",
+			out:  "",
+			want: map[string]gitState{},
+		},
+		{
+			name: "simple entries",
+			out:  "?? untracked.txt\x00 M modified.txt\x00M  staged.txt\x00",
+			want: map[string]gitState{
+				"untracked.txt": gitUntracked,
+				"modified.txt":  gitModified,
+				"staged.txt":    gitStaged,
+			},
+		},
+		{
+			name: "nested path collapses to top component",
+			out:  "?? sub/dir/file.txt\x00",
+			want: map[string]gitState{
+				"sub": gitUntracked,
+			},
+		},
+		{
+			name: "rename skips the original-path field",
+			out:  "R  new.txt\x00old.txt\x00",
+			want: map[string]gitState{
+				"new.txt": gitStaged,
+			},
+		},
+		{
+			name: "most severe state wins when entries collide",
+			out:  "?? dir/a.txt\x00M  dir/b.txt\x00",
+			want: map[string]gitState{
+				"dir": gitStaged,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseGitStatus(tt.out)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGitStatus(%q) = %v, want %v", tt.out, got, tt.want)
+			}
+		})
+	}
+}
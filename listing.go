@@ -0,0 +1,160 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	. "strings"
+	"time"
+)
+
+// sortMode controls the order pane.list() arranges directory entries in.
+type sortMode int
+
+const (
+	sortByName sortMode = iota
+	sortBySize
+	sortByMTime
+	sortByExtension
+	sortByNatural
+)
+
+// sortModes is the cycle order for keySortCycle.
+var sortModes = []sortMode{sortByName, sortBySize, sortByMTime, sortByExtension, sortByNatural}
+
+// setFilter commits a new persistent filter, compiling it as a regexp when
+// it's wrapped in slashes (e.g. "/\\.go$/"), otherwise matching as a plain
+// substring.
+func (p *pane) setFilter(filter string) {
+	p.filter = filter
+	p.filterRegex = nil
+	if len(filter) >= 2 && HasPrefix(filter, "/") && HasSuffix(filter, "/") {
+		if re, err := regexp.Compile(filter[1 : len(filter)-1]); err == nil {
+			p.filterRegex = re
+		}
+	}
+}
+
+func (p *pane) matchesFilter(name string) bool {
+	if p.filter == "" {
+		return true
+	}
+	if p.filterRegex != nil {
+		return p.filterRegex.MatchString(name)
+	}
+	return Contains(name, p.filter)
+}
+
+func (p *pane) cycleSortMode() {
+	for i, mode := range sortModes {
+		if mode == p.sortMode {
+			p.sortMode = sortModes[(i+1)%len(sortModes)]
+			return
+		}
+	}
+	p.sortMode = sortByName
+}
+
+// sortFiles sorts files in place. size/mtime sorting needs entry.Info(),
+// which is only stat'd lazily, when a mode that needs it is active.
+func sortFiles(files []fs.DirEntry, mode sortMode, desc bool) {
+	needsInfo := mode == sortBySize || mode == sortByMTime
+	infos := make([]fs.FileInfo, len(files))
+	if needsInfo {
+		for i, entry := range files {
+			infos[i], _ = entry.Info()
+		}
+	}
+
+	less := func(i, j int) bool {
+		switch mode {
+		case sortBySize:
+			return fileSize(infos[i]) < fileSize(infos[j])
+		case sortByMTime:
+			return fileModTime(infos[i]).Before(fileModTime(infos[j]))
+		case sortByExtension:
+			ai, aj := ToLower(filepath.Ext(files[i].Name())), ToLower(filepath.Ext(files[j].Name()))
+			if ai != aj {
+				return ai < aj
+			}
+			return files[i].Name() < files[j].Name()
+		case sortByNatural:
+			return naturalLess(files[i].Name(), files[j].Name())
+		default: // sortByName
+			return files[i].Name() < files[j].Name()
+		}
+	}
+
+	// files and infos must move together, since less() looks entries up by
+	// index into infos.
+	indexes := make([]int, len(files))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := indexes[i], indexes[j]
+		if desc {
+			a, b = b, a
+		}
+		return less(a, b)
+	})
+
+	sortedFiles := make([]fs.DirEntry, len(files))
+	for i, idx := range indexes {
+		sortedFiles[i] = files[idx]
+	}
+	copy(files, sortedFiles)
+}
+
+func fileSize(info fs.FileInfo) int64 {
+	if info == nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func fileModTime(info fs.FileInfo) time.Time {
+	if info == nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// naturalLess compares names the way a person would: runs of digits compare
+// numerically instead of lexically, so "file2" sorts before "file10".
+func naturalLess(a, b string) bool {
+	ai, bi := 0, 0
+	for ai < len(a) && bi < len(b) {
+		ac, bc := a[ai], b[bi]
+		if isDigit(ac) && isDigit(bc) {
+			as := ai
+			for ai < len(a) && isDigit(a[ai]) {
+				ai++
+			}
+			bs := bi
+			for bi < len(b) && isDigit(b[bi]) {
+				bi++
+			}
+			an := TrimLeft(a[as:ai], "0")
+			bn := TrimLeft(b[bs:bi], "0")
+			if len(an) != len(bn) {
+				return len(an) < len(bn)
+			}
+			if an != bn {
+				return an < bn
+			}
+			continue
+		}
+		if ac != bc {
+			return ac < bc
+		}
+		ai++
+		bi++
+	}
+	return len(a)-ai < len(b)-bi
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
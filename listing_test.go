@@ -0,0 +1,174 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"file2", "file10", true},
+		{"file10", "file2", false},
+		{"file2", "file2", false},
+		{"a", "b", true},
+		{"file02", "file10", true},
+		{"file002", "file02", false},
+		{"file2a", "file10a", true},
+		{"abc", "abc1", true},
+		{"file1", "file1", false},
+	}
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// fakeDirEntry is a minimal fs.DirEntry for exercising sort modes that only
+// look at the name, without needing a real directory on disk.
+type fakeDirEntry struct {
+	name string
+}
+
+func (f fakeDirEntry) Name() string               { return f.name }
+func (f fakeDirEntry) IsDir() bool                { return false }
+func (f fakeDirEntry) Type() fs.FileMode          { return 0 }
+func (f fakeDirEntry) Info() (fs.FileInfo, error) { return nil, nil }
+
+func names(files []fs.DirEntry) []string {
+	out := make([]string, len(files))
+	for i, f := range files {
+		out[i] = f.Name()
+	}
+	return out
+}
+
+func TestSortFilesByName(t *testing.T) {
+	files := []fs.DirEntry{
+		fakeDirEntry{"banana"},
+		fakeDirEntry{"apple"},
+		fakeDirEntry{"cherry"},
+	}
+	sortFiles(files, sortByName, false)
+	got := names(files)
+	want := []string{"apple", "banana", "cherry"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortFiles(name) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSortFilesByNameDescending(t *testing.T) {
+	files := []fs.DirEntry{
+		fakeDirEntry{"banana"},
+		fakeDirEntry{"apple"},
+		fakeDirEntry{"cherry"},
+	}
+	sortFiles(files, sortByName, true)
+	got := names(files)
+	want := []string{"cherry", "banana", "apple"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortFiles(name, desc) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSortFilesByExtension(t *testing.T) {
+	files := []fs.DirEntry{
+		fakeDirEntry{"b.go"},
+		fakeDirEntry{"a.md"},
+		fakeDirEntry{"c.go"},
+	}
+	sortFiles(files, sortByExtension, false)
+	got := names(files)
+	want := []string{"b.go", "c.go", "a.md"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortFiles(extension) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSortFilesByNatural(t *testing.T) {
+	files := []fs.DirEntry{
+		fakeDirEntry{"file10"},
+		fakeDirEntry{"file2"},
+		fakeDirEntry{"file1"},
+	}
+	sortFiles(files, sortByNatural, false)
+	got := names(files)
+	want := []string{"file1", "file2", "file10"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortFiles(natural) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSortFilesBySize(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "small", 1)
+	writeFile(t, dir, "large", 100)
+	writeFile(t, dir, "medium", 10)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortFiles(entries, sortBySize, false)
+	got := names(entries)
+	want := []string{"small", "medium", "large"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortFiles(size) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSortFilesByMTime(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "oldest", 1)
+	writeFile(t, dir, "middle", 1)
+	writeFile(t, dir, "newest", 1)
+
+	now := time.Now()
+	chtimes(t, dir, "oldest", now.Add(-2*time.Hour))
+	chtimes(t, dir, "middle", now.Add(-1*time.Hour))
+	chtimes(t, dir, "newest", now)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortFiles(entries, sortByMTime, false)
+	got := names(entries)
+	want := []string{"oldest", "middle", "newest"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortFiles(mtime) = %v, want %v", got, want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, dir, name string, size int) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func chtimes(t *testing.T, dir, name string, mtime time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,19 +2,12 @@ package main
 
 import (
 	"fmt"
-	"io"
-	"io/fs"
-	"math"
 	"os"
-	"os/exec"
-	"path"
 	"path/filepath"
-	. "strings"
 	"text/tabwriter"
-	"time"
-	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
@@ -27,29 +20,74 @@ var (
 	cursor  = lipgloss.NewStyle().Background(lipgloss.Color("#825DF2")).Foreground(lipgloss.Color("#FFFFFF"))
 	bar     = lipgloss.NewStyle().Background(lipgloss.Color("#5C5C5C")).Foreground(lipgloss.Color("#FFFFFF"))
 	search  = lipgloss.NewStyle().Background(lipgloss.Color("#499F1C")).Foreground(lipgloss.Color("#FFFFFF"))
+
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#499F1C")).Bold(true)
+	promptStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).PaddingLeft(1).PaddingRight(1)
+
+	// Per-file git status coloring; see gitstatus.go.
+	gitUntrackedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+	gitModifiedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#E5C07B"))
+	gitStagedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#499F1C"))
+	gitIgnoredStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#5C5C5C"))
+	gitConflictStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF00FF"))
 )
 
+// Key bindings. These used to be initialized right here, but they're now
+// built by buildKeys() in main() from ~/.config/llama/config.yaml (falling
+// back to the defaults commented alongside each one below); see config.go.
 var (
-	keyForceQuit = key.NewBinding(key.WithKeys("ctrl+c"))
-	keyQuit      = key.NewBinding(key.WithKeys("esc"))
-	keyOpen      = key.NewBinding(key.WithKeys("enter"))
-	keyBack      = key.NewBinding(key.WithKeys("backspace"))
-	keyUp        = key.NewBinding(key.WithKeys("up"))
-	keyDown      = key.NewBinding(key.WithKeys("down"))
-	keyLeft      = key.NewBinding(key.WithKeys("left"))
-	keyRight     = key.NewBinding(key.WithKeys("right"))
-	keyTop       = key.NewBinding(key.WithKeys("shift+up"))
-	keyBottom    = key.NewBinding(key.WithKeys("shift+down"))
-	keyLeftmost  = key.NewBinding(key.WithKeys("shift+left"))
-	keyRightmost = key.NewBinding(key.WithKeys("shift+right"))
-	keyVimUp     = key.NewBinding(key.WithKeys("k"))
-	keyVimDown   = key.NewBinding(key.WithKeys("j"))
-	keyVimLeft   = key.NewBinding(key.WithKeys("h"))
-	keyVimRight  = key.NewBinding(key.WithKeys("l"))
-	keyVimTop    = key.NewBinding(key.WithKeys("g"))
-	keyVimBottom = key.NewBinding(key.WithKeys("G"))
-	keySearch    = key.NewBinding(key.WithKeys("/"))
-	keyPreview   = key.NewBinding(key.WithKeys(" "))
+	keyForceQuit key.Binding // ctrl+c
+	keyQuit      key.Binding // esc
+	keyOpen      key.Binding // enter
+	keyBack      key.Binding // backspace
+	keyUp        key.Binding // up
+	keyDown      key.Binding // down
+	keyLeft      key.Binding // left
+	keyRight     key.Binding // right
+	keyTop       key.Binding // shift+up
+	keyBottom    key.Binding // shift+down
+	keyLeftmost  key.Binding // shift+left
+	keyRightmost key.Binding // shift+right
+	keyVimUp     key.Binding // k
+	keyVimDown   key.Binding // j
+	keyVimLeft   key.Binding // h
+	keyVimRight  key.Binding // l
+	keyVimTop    key.Binding // g
+	keyVimBottom key.Binding // G
+	keySearch    key.Binding // /
+	keyPreview   key.Binding // ctrl+p; Space used to toggle preview, see keySelect.
+
+	// Pane/workspace management. These are handled by the model before a
+	// key ever reaches the focused pane.
+	keySplitRight  key.Binding // ctrl+w, new column, side-by-side.
+	keySplitBelow  key.Binding // ctrl+e, new pane stacked below, in the same column.
+	keyClosePane   key.Binding // ctrl+q
+	keyCycleFocus  key.Binding // tab
+	keyCycleFocusR key.Binding // shift+tab
+	keyWiden       key.Binding // ctrl+right
+	keyNarrow      key.Binding // ctrl+left
+	keyTaller      key.Binding // ctrl+down
+	keyShorter     key.Binding // ctrl+up
+
+	// File operations.
+	keySelect key.Binding // space
+	keyDelete key.Binding // d
+	keyRename key.Binding // r
+	keyYank   key.Binding // y
+	keyCut    key.Binding // x
+	keyPaste  key.Binding // p
+	keyMkdir  key.Binding // n
+
+	// Listing controls: hidden files, sort mode, persistent filter.
+	keyToggleHidden key.Binding // .
+	keyFilter       key.Binding // f
+	keySortCycle    key.Binding // s
+	keySortReverse  key.Binding // S
+
+	// Bookmarks and jump-to-path; see bookmarks.go.
+	keyBookmark   key.Binding // m, followed by a mnemonic char to save under.
+	keyJumpMark   key.Binding // ', followed by a mnemonic char to jump to.
+	keyJumpPrompt key.Binding // z, fuzzy prompt over bookmarks and recents.
 )
 
 func main() {
@@ -73,13 +111,13 @@ func main() {
 	output := termenv.NewOutput(os.Stderr)
 	lipgloss.SetColorProfile(output.ColorProfile())
 
-	m := &model{
-		path:      startPath,
-		width:     80,
-		height:    60,
-		positions: make(map[string]position),
-	}
-	m.list()
+	cfg := loadConfig()
+	buildKeys(cfg.Keys)
+	buildSettings(cfg.Settings)
+	loadBookmarks()
+	loadRecentDirs()
+
+	m := newModel(startPath)
 
 	p := tea.NewProgram(m, tea.WithOutput(os.Stderr))
 	if _, err := p.Run(); err != nil {
@@ -88,91 +126,134 @@ func main() {
 	os.Exit(m.exitCode)
 }
 
+// column is a vertical stack of one or more panes, sharing a slice of the
+// workspace's width. Columns themselves are arranged left-to-right.
+type column struct {
+	panes       []*pane
+	focused     int     // Index into panes of the focused one.
+	widthFrac   float64 // Fraction of the workspace width this column occupies.
+	paneHeights []float64
+}
+
+// model is the workspace: a row of columns, each a stack of panes, plus the
+// terminal dimensions used to lay them out. Update() dispatches pane
+// management keys itself and forwards everything else to the focused pane;
+// View() is a compositor over lipgloss.JoinHorizontal/JoinVertical.
 type model struct {
-	path           string              // Current dir path we are looking at.
-	files          []fs.DirEntry       // Files we are looking at.
-	c, r           int                 // Selector position in columns and rows.
-	columns, rows  int                 // Displayed amount of rows and columns.
-	width, height  int                 // Terminal size.
-	offset         int                 // Scroll position.
-	positions      map[string]position // Map of cursor positions per path.
-	search         string              // Type to select files with this value.
-	searchMode     bool                // Whether type-to-select is active.
-	searchId       int                 // Search id to indicate what search we are currently on.
-	matchedIndexes []int               // List of char found indexes.
-	prevName       string              // Base name of previous directory before "up".
-	findPrevName   bool                // On View(), set c&r to point to prevName.
-	exitCode       int                 // Exit code.
-	previewMode    bool                // Whether preview is active.
-	previewContent string              // Content of preview.
+	columns    []*column // Row of columns; each column is a vertical stack of panes.
+	focusedCol int       // Index into columns of the focused one.
+	width      int       // Terminal width.
+	height     int       // Terminal height.
+	nextPaneId int       // Monotonic id assigned to new panes.
+	exitCode   int       // Exit code.
+
+	// File operations state; see fileops.go.
+	clipboardMode clipboardMode
+	clipboard     []string
+	prompt        promptKind
+	promptInput   textinput.Model
+	promptTarget  string
+	pasteQueue    []string
+	pasteDestDir  string
+	pasteRenameTo string // Overrides the destination basename for promptPasteAs; see advancePaste.
+
+	// Bookmarks and jump-to-path state; see bookmarks.go.
+	markPending      bool // Waiting for the mnemonic char after keyBookmark.
+	jumpCharPending  bool // Waiting for the mnemonic char after keyJumpMark.
+	jumpInput        textinput.Model
+	jumpEntriesCache []jumpEntry
+	jumpMatches      fuzzy.Matches
+	jumpSelected     int
 }
 
 type position struct {
-	c, r   int
-	offset int
+	c, r     int
+	offset   int
+	sortMode sortMode
+	sortDesc bool
+	filter   string
 }
 
-type (
-	clearSearchMsg int
-	previewMsg     string
-)
+func newModel(startPath string) *model {
+	m := &model{width: 80, height: 60}
+	m.columns = []*column{{
+		panes:       []*pane{newPane(m.nextPaneId, startPath)},
+		focused:     0,
+		widthFrac:   1,
+		paneHeights: []float64{1},
+	}}
+	m.nextPaneId++
+	m.focusedPane().list()
+	return m
+}
 
 func (m *model) Init() tea.Cmd {
 	return nil
 }
 
+func (m *model) focusedColumn() *column {
+	return m.columns[m.focusedCol]
+}
+
+func (m *model) focusedPane() *pane {
+	col := m.focusedColumn()
+	return col.panes[col.focused]
+}
+
+// allPanes returns every pane in the workspace, column-major.
+func (m *model) allPanes() []*pane {
+	var panes []*pane
+	for _, col := range m.columns {
+		panes = append(panes, col.panes...)
+	}
+	return panes
+}
+
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Reset position history as c&r changes.
-		m.positions = make(map[string]position)
-		// Keep cursor at same place.
-		fileName, ok := m.fileName()
-		if ok {
-			m.prevName = fileName
-			m.findPrevName = true
+		for _, p := range m.allPanes() {
+			// Reset position history as c&r changes, keeping the cursor on
+			// the same file once columns are recomputed for the new size.
+			p.positions = make(map[string]position)
+			if fileName, ok := p.fileName(); ok {
+				p.prevName = fileName
+				p.findPrevName = true
+			}
+			p.c, p.r = 0, 0
 		}
-		// Also, m.c&r no longer point to the correct indexes.
-		m.c = 0
-		m.r = 0
 		return m, nil
 
 	case tea.KeyMsg:
-		if m.searchMode {
-			if key.Matches(msg, keySearch) {
-				m.searchMode = false
-				return m, nil
-			} else if key.Matches(msg, keyBack) {
-				if len(m.search) > 0 {
-					m.search = m.search[:len(m.search)-1]
-					return m, nil
-				}
-			} else if msg.Type == tea.KeyRunes {
-				m.search += string(msg.Runes)
-				names := make([]string, len(m.files))
-				for i, fi := range m.files {
-					names[i] = fi.Name()
-				}
-				matches := fuzzy.Find(m.search, names)
-				if len(matches) > 0 {
-					m.matchedIndexes = matches[0].MatchedIndexes
-					index := matches[0].Index
-					m.c = index / m.rows
-					m.r = index % m.rows
+		if m.prompt != promptNone {
+			return m, m.updatePrompt(msg)
+		}
+
+		if m.markPending {
+			m.markPending = false
+			if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
+				saveBookmark(string(msg.Runes), m.focusedPane().path)
+			}
+			return m, nil
+		}
+
+		if m.jumpCharPending {
+			m.jumpCharPending = false
+			if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
+				if path, ok := bookmarks[string(msg.Runes)]; ok {
+					m.jumpTo(path)
 				}
-				m.updateOffset()
-				m.saveCursorPosition()
-				// Save search id to clear only current search after delay.
-				// User may have already started typing next search.
-				searchId := m.searchId
-				return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-					return clearSearchMsg(searchId)
-				})
 			}
+			return m, nil
 		}
 
+		// While the focused pane is capturing text (fuzzy search or filter
+		// editing), single-letter keys below must reach pane.update as
+		// ordinary runes instead of being intercepted as commands here.
+		textEntry := m.focusedPane().searchMode || m.focusedPane().filterMode
+
 		switch {
 		case key.Matches(msg, keyForceQuit):
 			_, _ = fmt.Fprintln(os.Stderr) // Keep last item visible after prompt.
@@ -180,438 +261,298 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case key.Matches(msg, keyQuit):
-			_, _ = fmt.Fprintln(os.Stderr) // Keep last item visible after prompt.
-			fmt.Println(m.path)            // Write to cd.
+			_, _ = fmt.Fprintln(os.Stderr)    // Keep last item visible after prompt.
+			fmt.Println(m.focusedPane().path) // Write to cd.
 			m.exitCode = 0
 			return m, tea.Quit
 
-		case key.Matches(msg, keyOpen):
-			m.searchMode = false
-			filePath, ok := m.filePath()
-			if !ok {
-				return m, nil
-			}
-			if fi := fileInfo(filePath); fi.IsDir() {
-				// Enter subdirectory.
-				m.path = filePath
-				if p, ok := m.positions[m.path]; ok {
-					m.c = p.c
-					m.r = p.r
-					m.offset = p.offset
-				} else {
-					m.c = 0
-					m.r = 0
-					m.offset = 0
-				}
-				m.list()
-			} else {
-				// Open file. This will block until complete.
-				return m, m.openEditor()
-			}
-
-		case key.Matches(msg, keyBack):
-			m.searchMode = false
-			m.prevName = filepath.Base(m.path)
-			m.path = filepath.Join(m.path, "..")
-			if p, ok := m.positions[m.path]; ok {
-				m.c = p.c
-				m.r = p.r
-				m.offset = p.offset
-			} else {
-				m.findPrevName = true
-			}
-			m.list()
-
-			if m.previewMode {
-				return m, m.previewCmd
-			} else {
-				return m, nil
-			}
+		case key.Matches(msg, keySplitRight):
+			m.splitRight()
+			return m, nil
 
-		case key.Matches(msg, keyUp):
-			m.moveUp()
+		case key.Matches(msg, keySplitBelow):
+			m.splitBelow()
+			return m, nil
 
-		case key.Matches(msg, keyTop, keyVimTop):
-			m.moveTop()
+		case key.Matches(msg, keyClosePane):
+			m.closeFocusedPane()
+			return m, nil
 
-		case key.Matches(msg, keyBottom, keyVimBottom):
-			m.moveBottom()
+		case key.Matches(msg, keyCycleFocus):
+			m.cycleFocus(1)
+			return m, nil
 
-		case key.Matches(msg, keyLeftmost):
-			m.moveLeftmost()
+		case key.Matches(msg, keyCycleFocusR):
+			m.cycleFocus(-1)
+			return m, nil
 
-		case key.Matches(msg, keyRightmost):
-			m.moveRightmost()
+		case key.Matches(msg, keyWiden):
+			m.resizeColumn(0.05)
+			return m, nil
 
-		case key.Matches(msg, keyVimUp):
-			if !m.searchMode {
-				m.moveUp()
-			}
+		case key.Matches(msg, keyNarrow):
+			m.resizeColumn(-0.05)
+			return m, nil
 
-		case key.Matches(msg, keyDown):
-			m.moveDown()
+		case key.Matches(msg, keyTaller):
+			m.resizePane(0.05)
+			return m, nil
 
-		case key.Matches(msg, keyVimDown):
-			if !m.searchMode {
-				m.moveDown()
-			}
+		case key.Matches(msg, keyShorter):
+			m.resizePane(-0.05)
+			return m, nil
 
-		case key.Matches(msg, keyLeft):
-			m.moveLeft()
+		case key.Matches(msg, keySelect) && !textEntry:
+			m.focusedPane().toggleSelection()
+			return m, nil
 
-		case key.Matches(msg, keyVimLeft):
-			if !m.searchMode {
-				m.moveLeft()
-			}
+		case key.Matches(msg, keyDelete) && !textEntry:
+			m.startDelete()
+			return m, nil
 
-		case key.Matches(msg, keyRight):
-			m.moveRight()
+		case key.Matches(msg, keyRename) && !textEntry:
+			m.startRename()
+			return m, nil
 
-		case key.Matches(msg, keyVimRight):
-			if !m.searchMode {
-				m.moveRight()
-			}
+		case key.Matches(msg, keyYank) && !textEntry:
+			m.clipboardMode = clipboardCopy
+			m.clipboard = m.focusedPane().selectedOrCurrent()
+			return m, nil
 
-		case key.Matches(msg, keySearch):
-			m.searchMode = true
-			m.searchId++
-			m.search = ""
-
-		case key.Matches(msg, keyPreview):
-			m.previewMode = !m.previewMode
-			// Reset position history as c&r changes.
-			m.positions = make(map[string]position)
-			// Keep cursor at same place.
-			fileName, ok := m.fileName()
-			if !ok {
-				return m, nil
-			}
-			m.prevName = fileName
-			m.findPrevName = true
-			if m.previewMode {
-				return m, tea.Sequence(tea.EnterAltScreen, m.previewCmd)
-			} else {
-				m.previewContent = ""
-				return m, tea.ExitAltScreen
-			}
-		}
+		case key.Matches(msg, keyCut) && !textEntry:
+			m.clipboardMode = clipboardCut
+			m.clipboard = m.focusedPane().selectedOrCurrent()
+			return m, nil
 
-		m.updateOffset()
-		m.saveCursorPosition()
+		case key.Matches(msg, keyPaste) && !textEntry:
+			m.startPaste()
+			return m, nil
 
-		if m.previewMode {
-			return m, m.previewCmd
-		} else {
+		case key.Matches(msg, keyMkdir) && !textEntry:
+			m.startMkdir()
 			return m, nil
-		}
 
-	case clearSearchMsg:
-		if m.searchId == int(msg) {
-			m.searchMode = false
-		}
+		case key.Matches(msg, keyBookmark) && !textEntry:
+			m.markPending = true
+			return m, nil
 
-	case previewMsg:
-		filePath := string(msg)
+		case key.Matches(msg, keyJumpMark) && !textEntry:
+			m.jumpCharPending = true
+			return m, nil
 
-		file, err := os.Open(filePath)
-		defer file.Close()
-		if err != nil {
-			m.previewContent = err.Error()
+		case key.Matches(msg, keyJumpPrompt) && !textEntry:
+			m.startJumpPrompt()
 			return m, nil
 		}
-		content, _ := io.ReadAll(file)
 
-		switch {
-		case utf8.Valid(content):
-			m.previewContent = Replace(string(content), "\t", "    ", -1)
+		cmd := m.focusedPane().update(msg)
+		return m, cmd
 
-		default:
-			m.previewContent = warning.Render("No preview available")
+	case clearSearchMsg, previewMsg:
+		// These are addressed to a specific pane id; route to all panes and
+		// let each decide (cheaply) whether it's the recipient.
+		var cmds []tea.Cmd
+		for _, p := range m.allPanes() {
+			if cmd := p.update(msg); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
 		}
+		return m, tea.Batch(cmds...)
 	}
 
 	return m, nil
 }
 
-func (m *model) moveUp() {
-	m.r--
-	if m.r < 0 {
-		m.r = m.rows - 1
-		m.c--
-	}
-	if m.c < 0 {
-		m.r = m.rows - 1 - (m.columns*m.rows - len(m.files))
-		m.c = m.columns - 1
-	}
+// splitRight opens a new column to the right of the focused one, starting at
+// the focused pane's current directory.
+func (m *model) splitRight() {
+	cur := m.focusedPane()
+	newCol := &column{
+		panes:       []*pane{newPane(m.nextPaneId, cur.path)},
+		focused:     0,
+		widthFrac:   1,
+		paneHeights: []float64{1},
+	}
+	m.nextPaneId++
+	newCol.panes[0].list()
+
+	insertAt := m.focusedCol + 1
+	m.columns = append(m.columns[:insertAt], append([]*column{newCol}, m.columns[insertAt:]...)...)
+	m.rebalanceColumns()
+	m.focusedCol = insertAt
 }
 
-func (m *model) moveDown() {
-	m.r++
-	if m.r >= m.rows {
-		m.r = 0
-		m.c++
-	}
-	if m.c >= m.columns {
-		m.c = 0
-	}
-	if m.c == m.columns-1 && (m.columns-1)*m.rows+m.r >= len(m.files) {
-		m.r = 0
-		m.c = 0
-	}
+// splitBelow adds a new pane under the focused one, within the same column.
+func (m *model) splitBelow() {
+	col := m.focusedColumn()
+	cur := m.focusedPane()
+	p := newPane(m.nextPaneId, cur.path)
+	m.nextPaneId++
+	p.list()
+
+	insertAt := col.focused + 1
+	col.panes = append(col.panes[:insertAt], append([]*pane{p}, col.panes[insertAt:]...)...)
+	col.rebalancePanes()
+	col.focused = insertAt
 }
 
-func (m *model) moveLeft() {
-	m.c--
-	if m.c < 0 {
-		m.c = m.columns - 1
-	}
-	if m.c == m.columns-1 && (m.columns-1)*m.rows+m.r >= len(m.files) {
-		m.r = m.rows - 1 - (m.columns*m.rows - len(m.files))
-		m.c = m.columns - 1
+// closeFocusedPane removes the focused pane. If it was the last pane in its
+// column, the column itself is removed; closing the last pane in the
+// workspace is a no-op, since there must always be somewhere to browse.
+func (m *model) closeFocusedPane() {
+	if len(m.columns) == 1 && len(m.columns[0].panes) == 1 {
+		return
+	}
+
+	col := m.focusedColumn()
+	col.panes = append(col.panes[:col.focused], col.panes[col.focused+1:]...)
+	if len(col.panes) == 0 {
+		m.columns = append(m.columns[:m.focusedCol], m.columns[m.focusedCol+1:]...)
+		m.rebalanceColumns()
+		if m.focusedCol >= len(m.columns) {
+			m.focusedCol = len(m.columns) - 1
+		}
+	} else {
+		col.rebalancePanes()
+		if col.focused >= len(col.panes) {
+			col.focused = len(col.panes) - 1
+		}
 	}
 }
 
-func (m *model) moveRight() {
-	m.c++
-	if m.c >= m.columns {
-		m.c = 0
-	}
-	if m.c == m.columns-1 && (m.columns-1)*m.rows+m.r >= len(m.files) {
-		m.r = m.rows - 1 - (m.columns*m.rows - len(m.files))
-		m.c = m.columns - 1
+// cycleFocus moves focus to the next (dir=1) or previous (dir=-1) pane,
+// walking column-major: through the focused column's panes first, then on to
+// the neighbouring column, wrapping from the last column to the first.
+func (m *model) cycleFocus(dir int) {
+	col := m.focusedColumn()
+	col.focused += dir
+	if col.focused >= 0 && col.focused < len(col.panes) {
+		return
+	}
+	col.focused -= dir // Keep a valid index in the column being left.
+
+	m.focusedCol = (m.focusedCol + dir + len(m.columns)) % len(m.columns)
+	next := m.focusedColumn()
+	if dir > 0 {
+		next.focused = 0
+	} else {
+		next.focused = len(next.panes) - 1
 	}
 }
 
-func (m *model) moveTop() {
-	m.r = 0
-}
-
-func (m *model) moveBottom() {
-	m.r = m.rows - 1
-	if m.c == m.columns-1 && (m.columns-1)*m.rows+m.r >= len(m.files) {
-		m.r = m.rows - 1 - (m.columns*m.rows - len(m.files))
+func (m *model) resizeColumn(delta float64) {
+	if len(m.columns) < 2 {
+		return
 	}
+	col := m.focusedColumn()
+	col.widthFrac = clampFrac(col.widthFrac+delta, len(m.columns))
+	m.rebalanceColumns()
 }
 
-func (m *model) moveLeftmost() {
-	m.c = 0
-}
-
-func (m *model) moveRightmost() {
-	m.c = m.columns - 1
-	if m.c == m.columns-1 && (m.columns-1)*m.rows+m.r >= len(m.files) {
-		m.r = m.rows - 1 - (m.columns*m.rows - len(m.files))
-		m.c = m.columns - 1
+func (m *model) resizePane(delta float64) {
+	col := m.focusedColumn()
+	if len(col.panes) < 2 {
+		return
 	}
+	col.paneHeights[col.focused] = clampFrac(col.paneHeights[col.focused]+delta, len(col.panes))
+	col.rebalancePanes()
 }
 
-func (m *model) View() string {
-	width := m.width
-	if m.previewMode {
-		width = m.width / 2
-	}
-	height := m.listHeight()
-
-	// If it's possible to fit all files in one column on a third of the screen,
-	// just use one column. Otherwise, let's squeeze listing in half of screen.
-	m.columns = len(m.files) / (height / 3)
-	if m.columns <= 0 {
-		m.columns = 1
-	}
-
-start:
-	// Let's try to fit everything in terminal width with this many columns.
-	// If we are not able to do it, decrease column number and goto start.
-	m.rows = int(math.Ceil(float64(len(m.files)) / float64(m.columns)))
-	names := make([][]string, m.columns)
-	n := 0
-	for i := 0; i < m.columns; i++ {
-		names[i] = make([]string, m.rows)
-		// Columns size is going to be of max file name size.
-		max := 0
-		for j := 0; j < m.rows; j++ {
-			name := ""
-			if n < len(m.files) {
-				name = m.files[n].Name()
-				if m.findPrevName && m.prevName == name {
-					m.c = i
-					m.r = j
-				}
-				if m.files[n].IsDir() {
-					// Dirs should have a slash at the end.
-					name += "/"
-				}
-				n++
-			}
-			if max < len(name) {
-				max = len(name)
-			}
-			names[i][j] = name
-		}
-		// Append spaces to make all names in one column of same size.
-		for j := 0; j < m.rows; j++ {
-			names[i][j] += Repeat(" ", max-len(names[i][j]))
-		}
+// clampFrac keeps a fraction within [0.1, 1] and leaves enough for at least
+// n-1 other equally-tiny siblings.
+func clampFrac(f float64, n int) float64 {
+	min := 0.1
+	max := 1 - min*float64(n-1)
+	if f < min {
+		return min
 	}
-
-	const separator = "    " // Separator between columns.
-	for j := 0; j < m.rows; j++ {
-		row := make([]string, m.columns)
-		for i := 0; i < m.columns; i++ {
-			row[i] = names[i][j]
-		}
-		if len(Join(row, separator)) > width && m.columns > 1 {
-			// Yep. No luck, let's decrease number of columns and try one more time.
-			m.columns--
-			goto start
-		}
+	if f > max {
+		return max
 	}
+	return f
+}
 
-	// If we need to select previous directory on "up".
-	if m.findPrevName {
-		m.findPrevName = false
-		m.updateOffset()
-		m.saveCursorPosition()
+// rebalanceColumns renormalizes widthFrac across columns so they sum to 1,
+// preserving relative proportions set by resizeColumn.
+func (m *model) rebalanceColumns() {
+	if len(m.columns) == 0 {
+		return
 	}
-
-	// Let's add colors from git status to file names.
-	output := make([]string, m.rows)
-	for j := 0; j < m.rows; j++ {
-		row := make([]string, m.columns)
-		for i := 0; i < m.columns; i++ {
-			if i == m.c && j == m.r {
-				row[i] = cursor.Render(names[i][j])
-			} else {
-				row[i] = names[i][j]
-			}
+	total := 0.0
+	for _, col := range m.columns {
+		if col.widthFrac <= 0 {
+			col.widthFrac = 1
 		}
-		output[j] = Join(row, separator)
-	}
-	if len(output) >= m.offset+height {
-		output = output[m.offset : m.offset+height]
+		total += col.widthFrac
 	}
-
-	// Location bar (grey).
-	location := m.path
-	if userHomeDir, err := os.UserHomeDir(); err == nil {
-		location = Replace(m.path, userHomeDir, "~", 1)
-	}
-	// Filter bar (green).
-	filter := ""
-	if m.searchMode {
-		filter = "/" + m.search
-	}
-	barLen := len(location) + len(filter)
-	if barLen > width {
-		// TODO: this panics as soon as we have a filter and the path is too long.
-		// runtime error: slice bounds out of range [12:11]
-		location = location[barLen-width:]
-	}
-	bar := bar.Render(location) + search.Render(filter)
-
-	if len(m.files) == 0 {
-		return bar + "\n" + warning.Render("No files")
-	}
-
-	main := bar + "\n" + Join(output, "\n")
-
-	if m.previewMode {
-		return lipgloss.JoinHorizontal(
-			lipgloss.Top,
-			main,
-			preview.
-				MaxHeight(m.height).
-				Render(m.previewContent),
-		)
-	} else {
-		return main
-	}
-}
-
-func (m *model) list() {
-	var err error
-	m.files = nil
-
-	// ReadDir already returns files and dirs sorted by filename.
-	m.files, err = os.ReadDir(m.path)
-	if err != nil {
-		panic(err)
+	for _, col := range m.columns {
+		col.widthFrac /= total
 	}
 }
 
-func (m *model) listHeight() int {
-	return m.height - 1 // Subtract 1 for location bar.
-}
-
-func (m *model) updateOffset() {
-	height := m.listHeight()
-	// Scrolling down.
-	if m.r >= m.offset+height {
-		m.offset = m.r - height + 1
-	}
-	// Scrolling up.
-	if m.r < m.offset {
-		m.offset = m.r
+// rebalancePanes renormalizes paneHeights within a column so they sum to 1.
+func (c *column) rebalancePanes() {
+	for len(c.paneHeights) < len(c.panes) {
+		c.paneHeights = append(c.paneHeights, 1)
 	}
-	// Don't scroll more than there are rows.
-	if m.offset > m.rows-height && m.rows > height {
-		m.offset = m.rows - height
-	}
-}
-
-// Save position to restore later.
-func (m *model) saveCursorPosition() {
-	m.positions[m.path] = position{
-		c:      m.c,
-		r:      m.r,
-		offset: m.offset,
+	c.paneHeights = c.paneHeights[:len(c.panes)]
+	total := 0.0
+	for _, h := range c.paneHeights {
+		if h <= 0 {
+			h = 1
+		}
+		total += h
 	}
-}
-
-func (m *model) fileName() (string, bool) {
-	i := m.c*m.rows + m.r
-	if i >= len(m.files) {
-		return "", false
+	for i, h := range c.paneHeights {
+		if h <= 0 {
+			h = 1
+		}
+		c.paneHeights[i] = h / total
 	}
-	return m.files[i].Name(), true
 }
 
-func (m *model) filePath() (string, bool) {
-	fileName, ok := m.fileName()
-	if !ok {
-		return fileName, false
+func (m *model) View() string {
+	colViews := make([]string, len(m.columns))
+	for ci, col := range m.columns {
+		colWidth := int(float64(m.width) * col.widthFrac)
+
+		paneViews := make([]string, len(col.panes))
+		for pi, p := range col.panes {
+			paneHeight := int(float64(m.height) * col.paneHeights[pi])
+			focused := ci == m.focusedCol && pi == col.focused
+			paneViews[pi] = p.View(colWidth, paneHeight, focused)
+		}
+		colViews[ci] = lipgloss.JoinVertical(lipgloss.Left, paneViews...)
 	}
-	return path.Join(m.path, fileName), true
-}
+	workspace := lipgloss.JoinHorizontal(lipgloss.Top, colViews...)
 
-func (m *model) openEditor() tea.Cmd {
-	filePath, ok := m.filePath()
-	if !ok {
-		return nil
+	if overlay := m.promptView(); overlay != "" {
+		return workspace + "\n" + overlay
 	}
-	execCmd := exec.Command(lookup([]string{"LLAMA_EDITOR", "EDITOR"}, "less"), filePath)
-	return tea.ExecProcess(execCmd, func(err error) tea.Msg {
-		// Note: we could return a message here indicating that editing is
-		// finished and altering our application about any errors. For now,
-		// however, that's not necessary.
-		return nil
-	})
+	return workspace
 }
 
-func (m *model) previewCmd() tea.Msg {
-	filePath, ok := m.filePath()
-	if !ok {
-		return nil
-	}
-	return previewMsg(filePath)
+// promptView renders the active modal overlay (delete confirmation, rename,
+// mkdir, paste conflict, or jump prompt), or "" if none is active.
+func (m *model) promptView() string {
+	switch m.prompt {
+	case promptConfirmDelete:
+		return promptStyle.Render(m.promptTarget)
+	case promptOverwrite:
+		return promptStyle.Render(fmt.Sprintf("%q exists: overwrite/skip/rename? (o/s/r)", m.promptTarget))
+	case promptRename, promptMkdir, promptPasteAs:
+		return promptStyle.Render(m.promptInput.View())
+	case promptJump:
+		return promptStyle.Render(m.jumpView())
+	}
+	return ""
 }
 
-func fileInfo(path string) os.FileInfo {
-	fi, err := os.Stat(path)
-	if err != nil {
-		panic(err)
-	}
-	return fi
+// fileInfo stats path. Callers must handle a non-nil error themselves: the
+// entry may have been removed by a mutating op in another pane since this
+// pane last listed its directory.
+func fileInfo(path string) (os.FileInfo, error) {
+	return os.Stat(path)
 }
 
 func lookup(names []string, val string) string {
@@ -634,6 +575,16 @@ func usage() {
 	put("    Enter\tEnter directory")
 	put("    Backspace\tExit directory")
 	put("    /\tFuzzy search")
+	put("    Ctrl+W\tSplit pane to the right")
+	put("    Ctrl+E\tSplit pane below")
+	put("    Tab, Shift+Tab\tCycle focused pane")
+	put("    Ctrl+Q\tClose focused pane")
+	put("    .\tToggle hidden files")
+	put("    f\tSet a persistent filter")
+	put("    s, S\tCycle sort mode, reverse sort")
+	put("    m<char>\tBookmark current directory as <char>")
+	put("    '<char>\tJump to bookmark <char>")
+	put("    z\tFuzzy jump to a bookmark or recent directory")
 	put("    Esc\tExit with cd")
 	put("    Ctrl+C\tExit without cd")
 	_ = w.Flush()
@@ -0,0 +1,666 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	. "strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// pane is a single, independently navigable directory browser. A model can
+// hold several of them, arranged into columns by the compositor in View().
+type pane struct {
+	id             int                 // Unique id, used to route async messages back to this pane.
+	path           string              // Current dir path we are looking at.
+	files          []fs.DirEntry       // Files we are looking at.
+	c, r           int                 // Selector position in columns and rows.
+	columns, rows  int                 // Displayed amount of rows and columns.
+	offset         int                 // Scroll position.
+	positions      map[string]position // Map of cursor positions per path.
+	search         string              // Type to select files with this value.
+	searchMode     bool                // Whether type-to-select is active.
+	searchId       int                 // Search id to indicate what search we are currently on.
+	matchedIndexes []int               // List of char found indexes.
+	prevName       string              // Base name of previous directory before "up".
+	findPrevName   bool                // On View(), set c&r to point to prevName.
+	previewMode    bool                // Whether preview is active.
+	previewContent string              // Content of preview.
+	lastHeight     int                 // Listing height as of the last View(), used to clamp offset.
+	selection      map[string]struct{} // Set of selected absolute file paths, for bulk file operations.
+	settings       settingsConfig      // Effective settings, copied in at creation time; see config.go.
+	showHidden     bool                // Whether dotfiles are shown; see listing.go.
+	sortMode       sortMode            // Current sort mode; see listing.go.
+	sortDesc       bool                // Whether sortMode is applied in reverse.
+	filter         string              // Persistent filter expression (substring or /regex/); see listing.go.
+	filterRegex    *regexp.Regexp      // Compiled form of filter, if it's a /regex/.
+	filterMode     bool                // Whether the filter prompt is being edited.
+	filterInput    string              // In-progress filter text while filterMode is active.
+	gitStatus      map[string]gitState // Per-entry git status for p.path, keyed by basename; see gitstatus.go.
+}
+
+// previewMsg and clearSearchMsg now carry the id of the pane they belong to,
+// so they can be routed back to the right pane even if focus moved on.
+type (
+	clearSearchMsg struct {
+		paneId int
+		id     int
+	}
+	previewMsg struct {
+		paneId int
+		path   string
+	}
+)
+
+func newPane(id int, startPath string) *pane {
+	return &pane{
+		id:         id,
+		path:       startPath,
+		positions:  make(map[string]position),
+		selection:  make(map[string]struct{}),
+		settings:   settings,
+		showHidden: settings.Hidden,
+	}
+}
+
+// toggleSelection flips the selection state of the file under the cursor.
+func (p *pane) toggleSelection() {
+	filePath, ok := p.filePath()
+	if !ok {
+		return
+	}
+	if _, ok := p.selection[filePath]; ok {
+		delete(p.selection, filePath)
+	} else {
+		p.selection[filePath] = struct{}{}
+	}
+}
+
+// selectedOrCurrent returns the selected paths, or, if nothing is selected,
+// the single path under the cursor. This is the target set for file
+// operations like delete/yank/cut.
+func (p *pane) selectedOrCurrent() []string {
+	if len(p.selection) > 0 {
+		paths := make([]string, 0, len(p.selection))
+		for path := range p.selection {
+			paths = append(paths, path)
+		}
+		return paths
+	}
+	if filePath, ok := p.filePath(); ok {
+		return []string{filePath}
+	}
+	return nil
+}
+
+// update handles a key/tick/preview message for this pane alone. Pane
+// management keys (new split, close, cycle focus, resize) are handled by the
+// model before update is ever called.
+func (p *pane) update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if p.filterMode {
+			switch {
+			case key.Matches(msg, keyFilter):
+				// Pressing the filter key again cancels editing, same as
+				// keySearch toggles searchMode off below.
+				p.filterMode = false
+			case msg.Type == tea.KeyEnter:
+				p.filterMode = false
+				p.setFilter(p.filterInput)
+				p.list()
+				p.c, p.r, p.offset = 0, 0, 0
+			case key.Matches(msg, keyBack):
+				if len(p.filterInput) > 0 {
+					p.filterInput = p.filterInput[:len(p.filterInput)-1]
+				}
+			case msg.Type == tea.KeyRunes:
+				p.filterInput += string(msg.Runes)
+			}
+			return nil
+		}
+
+		if p.searchMode {
+			if key.Matches(msg, keySearch) {
+				p.searchMode = false
+				return nil
+			} else if key.Matches(msg, keyBack) {
+				if len(p.search) > 0 {
+					p.search = p.search[:len(p.search)-1]
+					return nil
+				}
+			} else if msg.Type == tea.KeyRunes {
+				p.search += string(msg.Runes)
+				names := make([]string, len(p.files))
+				for i, fi := range p.files {
+					names[i] = fi.Name()
+				}
+				matches := fuzzy.Find(p.search, names)
+				if len(matches) > 0 {
+					p.matchedIndexes = matches[0].MatchedIndexes
+					index := matches[0].Index
+					p.c = index / p.rows
+					p.r = index % p.rows
+				}
+				p.updateOffset()
+				p.saveCursorPosition()
+				// Save search id to clear only current search after delay.
+				// User may have already started typing next search.
+				searchId := p.searchId
+				paneId := p.id
+				return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+					return clearSearchMsg{paneId: paneId, id: searchId}
+				})
+			}
+		}
+
+		switch {
+		case key.Matches(msg, keyOpen):
+			p.searchMode = false
+			filePath, ok := p.filePath()
+			if !ok {
+				return nil
+			}
+			fi, err := fileInfo(filePath)
+			if err != nil {
+				// Vanished (e.g. removed from another pane); refresh
+				// instead of acting on a stale entry.
+				p.list()
+				return nil
+			}
+			if fi.IsDir() {
+				// Enter subdirectory.
+				p.enterPath(filePath)
+			} else {
+				// Open file. This will block until complete.
+				return p.openEditor()
+			}
+
+		case key.Matches(msg, keyBack):
+			p.searchMode = false
+			p.prevName = filepath.Base(p.path)
+			p.path = filepath.Join(p.path, "..")
+			recordRecentDir(p.path)
+			if pos, ok := p.positions[p.path]; ok {
+				p.c, p.r, p.offset = pos.c, pos.r, pos.offset
+				p.sortMode, p.sortDesc = pos.sortMode, pos.sortDesc
+				p.setFilter(pos.filter)
+			} else {
+				p.sortMode, p.sortDesc = sortByName, false
+				p.setFilter("")
+				p.findPrevName = true
+			}
+			p.list()
+
+			if p.previewMode {
+				return p.previewCmd
+			}
+			return nil
+
+		case key.Matches(msg, keyUp):
+			p.moveUp()
+
+		case key.Matches(msg, keyTop, keyVimTop):
+			p.moveTop()
+
+		case key.Matches(msg, keyBottom, keyVimBottom):
+			p.moveBottom()
+
+		case key.Matches(msg, keyLeftmost):
+			p.moveLeftmost()
+
+		case key.Matches(msg, keyRightmost):
+			p.moveRightmost()
+
+		case key.Matches(msg, keyVimUp):
+			if !p.searchMode {
+				p.moveUp()
+			}
+
+		case key.Matches(msg, keyDown):
+			p.moveDown()
+
+		case key.Matches(msg, keyVimDown):
+			if !p.searchMode {
+				p.moveDown()
+			}
+
+		case key.Matches(msg, keyLeft):
+			p.moveLeft()
+
+		case key.Matches(msg, keyVimLeft):
+			if !p.searchMode {
+				p.moveLeft()
+			}
+
+		case key.Matches(msg, keyRight):
+			p.moveRight()
+
+		case key.Matches(msg, keyVimRight):
+			if !p.searchMode {
+				p.moveRight()
+			}
+
+		case key.Matches(msg, keySearch):
+			p.searchMode = true
+			p.searchId++
+			p.search = ""
+
+		case key.Matches(msg, keyPreview):
+			p.previewMode = !p.previewMode
+			// Reset position history as c&r changes.
+			p.positions = make(map[string]position)
+			// Keep cursor at same place.
+			fileName, ok := p.fileName()
+			if !ok {
+				return nil
+			}
+			p.prevName = fileName
+			p.findPrevName = true
+			if p.previewMode {
+				return p.previewCmd
+			}
+			p.previewContent = ""
+			return nil
+
+		case key.Matches(msg, keyToggleHidden):
+			p.showHidden = !p.showHidden
+			p.list()
+
+		case key.Matches(msg, keyFilter):
+			p.filterMode = true
+			p.filterInput = p.filter
+
+		case key.Matches(msg, keySortCycle):
+			p.cycleSortMode()
+			p.list()
+
+		case key.Matches(msg, keySortReverse):
+			p.sortDesc = !p.sortDesc
+			p.list()
+		}
+
+		p.updateOffset()
+		p.saveCursorPosition()
+
+		if p.previewMode {
+			return p.previewCmd
+		}
+		return nil
+
+	case clearSearchMsg:
+		if msg.paneId == p.id && p.searchId == msg.id {
+			p.searchMode = false
+		}
+
+	case previewMsg:
+		if msg.paneId != p.id {
+			return nil
+		}
+		p.previewContent = renderPreview(msg.path, p.settings)
+	}
+
+	return nil
+}
+
+// View renders this pane's content (location bar, listing, and preview if
+// active) within the given width/height, which the compositor in the
+// model's View() has already allocated to it.
+func (p *pane) View(width, height int, focused bool) string {
+	listWidth := width
+	if p.previewMode {
+		listWidth = width / 2
+	}
+	listHeight := p.listHeight(height)
+	p.lastHeight = listHeight
+
+	// If it's possible to fit all files in one column on a third of the screen,
+	// just use one column. Otherwise, let's squeeze listing in half of screen.
+	columnDivisor := listHeight / 3
+	if columnDivisor < 1 {
+		// A pane this short (e.g. several stacked splits on a small
+		// terminal) would otherwise divide by zero.
+		columnDivisor = 1
+	}
+	p.columns = len(p.files) / columnDivisor
+	if p.columns <= 0 {
+		p.columns = 1
+	}
+
+start:
+	// Let's try to fit everything in pane width with this many columns.
+	// If we are not able to do it, decrease column number and goto start.
+	p.rows = int(math.Ceil(float64(len(p.files)) / float64(p.columns)))
+	names := make([][]string, p.columns)
+	paths := make([][]string, p.columns)
+	n := 0
+	for i := 0; i < p.columns; i++ {
+		names[i] = make([]string, p.rows)
+		paths[i] = make([]string, p.rows)
+		// Columns size is going to be of max file name size.
+		max := 0
+		for j := 0; j < p.rows; j++ {
+			name := ""
+			if n < len(p.files) {
+				name = p.files[n].Name()
+				paths[i][j] = path.Join(p.path, name)
+				if p.findPrevName && p.prevName == name {
+					p.c = i
+					p.r = j
+				}
+				if p.files[n].IsDir() {
+					// Dirs should have a slash at the end.
+					name += "/"
+				}
+				n++
+			}
+			if max < len(name) {
+				max = len(name)
+			}
+			names[i][j] = name
+		}
+		// Append spaces to make all names in one column of same size.
+		for j := 0; j < p.rows; j++ {
+			names[i][j] += Repeat(" ", max-len(names[i][j]))
+		}
+	}
+
+	const separator = "    " // Separator between columns.
+	for j := 0; j < p.rows; j++ {
+		row := make([]string, p.columns)
+		for i := 0; i < p.columns; i++ {
+			row[i] = names[i][j]
+		}
+		if len(Join(row, separator)) > listWidth && p.columns > 1 {
+			// Yep. No luck, let's decrease number of columns and try one more time.
+			p.columns--
+			goto start
+		}
+	}
+
+	// If we need to select previous directory on "up".
+	if p.findPrevName {
+		p.findPrevName = false
+		p.updateOffset()
+		p.saveCursorPosition()
+	}
+
+	output := make([]string, p.rows)
+	for j := 0; j < p.rows; j++ {
+		row := make([]string, p.columns)
+		for i := 0; i < p.columns; i++ {
+			_, selected := p.selection[paths[i][j]]
+			state := p.gitStatus[filepath.Base(paths[i][j])]
+			switch {
+			case focused && i == p.c && j == p.r:
+				row[i] = cursor.Render(names[i][j])
+			case selected:
+				row[i] = selectedStyle.Render(names[i][j])
+			case state != gitNone:
+				row[i] = gitStatusStyle(state).Render(names[i][j])
+			default:
+				row[i] = names[i][j]
+			}
+		}
+		output[j] = Join(row, separator)
+	}
+	if len(output) >= p.offset+listHeight {
+		output = output[p.offset : p.offset+listHeight]
+	}
+
+	// Location bar (grey).
+	location := p.path
+	if userHomeDir, err := os.UserHomeDir(); err == nil {
+		location = Replace(p.path, userHomeDir, "~", 1)
+	}
+	if len(p.selection) > 0 {
+		location += fmt.Sprintf(" [%d selected]", len(p.selection))
+	}
+	if p.filter != "" {
+		location += " [filter: " + p.filter + "]"
+	}
+	// Filter bar (green): fuzzy search, or the persistent filter being edited.
+	searchBar := ""
+	switch {
+	case p.searchMode:
+		searchBar = "/" + p.search
+	case p.filterMode:
+		searchBar = "filter: " + p.filterInput
+	}
+	barLen := len(location) + len(searchBar)
+	if barLen > width {
+		// searchBar alone can already exceed width (e.g. a narrow split with
+		// a long search/filter string), so the naive start index can run
+		// past the end of location; clamp it instead of slicing OOB.
+		start := barLen - width
+		if start > len(location) {
+			start = len(location)
+		}
+		location = location[start:]
+	}
+	renderedBar := bar.Render(location) + search.Render(searchBar)
+
+	if len(p.files) == 0 {
+		return renderedBar + "\n" + warning.Render("No files")
+	}
+
+	main := renderedBar + "\n" + Join(output, "\n")
+
+	if p.previewMode {
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			main,
+			preview.
+				MaxHeight(height).
+				Render(p.previewContent),
+		)
+	}
+	return main
+}
+
+func (p *pane) moveUp() {
+	p.r--
+	if p.r < 0 {
+		p.r = p.rows - 1
+		p.c--
+	}
+	if p.c < 0 {
+		p.r = p.rows - 1 - (p.columns*p.rows - len(p.files))
+		p.c = p.columns - 1
+	}
+}
+
+func (p *pane) moveDown() {
+	p.r++
+	if p.r >= p.rows {
+		p.r = 0
+		p.c++
+	}
+	if p.c >= p.columns {
+		p.c = 0
+	}
+	if p.c == p.columns-1 && (p.columns-1)*p.rows+p.r >= len(p.files) {
+		p.r = 0
+		p.c = 0
+	}
+}
+
+func (p *pane) moveLeft() {
+	p.c--
+	if p.c < 0 {
+		p.c = p.columns - 1
+	}
+	if p.c == p.columns-1 && (p.columns-1)*p.rows+p.r >= len(p.files) {
+		p.r = p.rows - 1 - (p.columns*p.rows - len(p.files))
+		p.c = p.columns - 1
+	}
+}
+
+func (p *pane) moveRight() {
+	p.c++
+	if p.c >= p.columns {
+		p.c = 0
+	}
+	if p.c == p.columns-1 && (p.columns-1)*p.rows+p.r >= len(p.files) {
+		p.r = p.rows - 1 - (p.columns*p.rows - len(p.files))
+		p.c = p.columns - 1
+	}
+}
+
+func (p *pane) moveTop() {
+	p.r = 0
+}
+
+func (p *pane) moveBottom() {
+	p.r = p.rows - 1
+	if p.c == p.columns-1 && (p.columns-1)*p.rows+p.r >= len(p.files) {
+		p.r = p.rows - 1 - (p.columns*p.rows - len(p.files))
+	}
+}
+
+func (p *pane) moveLeftmost() {
+	p.c = 0
+}
+
+func (p *pane) moveRightmost() {
+	p.c = p.columns - 1
+	if p.c == p.columns-1 && (p.columns-1)*p.rows+p.r >= len(p.files) {
+		p.r = p.rows - 1 - (p.columns*p.rows - len(p.files))
+		p.c = p.columns - 1
+	}
+}
+
+// list reloads the current directory's entries. If p.path no longer exists
+// (e.g. removed by a mutating op in another pane), it walks up to the
+// nearest surviving ancestor instead of panicking.
+func (p *pane) list() {
+	for {
+		entries, err := os.ReadDir(p.path)
+		if err == nil {
+			files := make([]fs.DirEntry, 0, len(entries))
+			for _, entry := range entries {
+				if !p.showHidden && HasPrefix(entry.Name(), ".") {
+					continue
+				}
+				if !p.matchesFilter(entry.Name()) {
+					continue
+				}
+				files = append(files, entry)
+			}
+			sortFiles(files, p.sortMode, p.sortDesc)
+			p.files = files
+			p.refreshGitStatus()
+			return
+		}
+
+		parent := filepath.Dir(p.path)
+		if parent == p.path {
+			// Reached the root and it's still unreadable; give up with an
+			// empty listing rather than looping forever.
+			p.files = nil
+			return
+		}
+		p.path = parent
+		p.c, p.r, p.offset = 0, 0, 0
+	}
+}
+
+func (p *pane) listHeight(height int) int {
+	return height - 1 // Subtract 1 for location bar.
+}
+
+func (p *pane) updateOffset() {
+	height := p.lastHeight
+	if height <= 0 {
+		height = 1
+	}
+	// Scrolling down.
+	if p.r >= p.offset+height {
+		p.offset = p.r - height + 1
+	}
+	// Scrolling up.
+	if p.r < p.offset {
+		p.offset = p.r
+	}
+	// Don't scroll more than there are rows.
+	if p.offset > p.rows-height && p.rows > height {
+		p.offset = p.rows - height
+	}
+}
+
+// Save position to restore later.
+func (p *pane) saveCursorPosition() {
+	p.positions[p.path] = position{
+		c:        p.c,
+		r:        p.r,
+		offset:   p.offset,
+		sortMode: p.sortMode,
+		sortDesc: p.sortDesc,
+		filter:   p.filter,
+	}
+}
+
+// enterPath switches the pane to path, restoring its cursor, sort mode and
+// filter from a previous visit if we have one.
+func (p *pane) enterPath(path string) {
+	p.path = path
+	recordRecentDir(path)
+	if pos, ok := p.positions[p.path]; ok {
+		p.c, p.r, p.offset = pos.c, pos.r, pos.offset
+		p.sortMode, p.sortDesc = pos.sortMode, pos.sortDesc
+		p.setFilter(pos.filter)
+	} else {
+		p.c, p.r, p.offset = 0, 0, 0
+		p.sortMode, p.sortDesc = sortByName, false
+		p.setFilter("")
+	}
+	p.list()
+}
+
+func (p *pane) fileName() (string, bool) {
+	i := p.c*p.rows + p.r
+	if i >= len(p.files) {
+		return "", false
+	}
+	return p.files[i].Name(), true
+}
+
+func (p *pane) filePath() (string, bool) {
+	fileName, ok := p.fileName()
+	if !ok {
+		return fileName, false
+	}
+	return path.Join(p.path, fileName), true
+}
+
+func (p *pane) openEditor() tea.Cmd {
+	filePath, ok := p.filePath()
+	if !ok {
+		return nil
+	}
+	execCmd := exec.Command(p.settings.Editor, filePath)
+	return tea.ExecProcess(execCmd, func(err error) tea.Msg {
+		// Note: we could return a message here indicating that editing is
+		// finished and altering our application about any errors. For now,
+		// however, that's not necessary.
+		return nil
+	})
+}
+
+func (p *pane) previewCmd() tea.Msg {
+	filePath, ok := p.filePath()
+	if !ok {
+		return nil
+	}
+	return previewMsg{paneId: p.id, path: filePath}
+}
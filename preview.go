@@ -0,0 +1,219 @@
+package main
+
+import (
+	"container/list"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	. "strings"
+	"unicode/utf8"
+
+	"github.com/muesli/termenv"
+)
+
+// previewByteCap bounds the preview text: the raw text fallback reads at most
+// this many bytes of a file, and output from a pager, archive handler or
+// chafa is cut to this length once the command has finished. Image files are
+// read whole before renderImagePreview's own size check.
+const previewByteCap = 64 * 1024
+
+// archiveHandlers lists the files a plain text/binary preview can't do
+// anything useful with; each gets a command that prints a textual summary
+// instead (a file listing for archives, extracted text for PDFs, ...).
+var archiveHandlers = map[string]func(path string) *exec.Cmd{
+	".zip": func(path string) *exec.Cmd { return exec.Command("unzip", "-l", path) },
+	".tar": func(path string) *exec.Cmd { return exec.Command("tar", "-tf", path) },
+	".gz":  func(path string) *exec.Cmd { return exec.Command("tar", "-tzf", path) },
+	".tgz": func(path string) *exec.Cmd { return exec.Command("tar", "-tzf", path) },
+	".pdf": func(path string) *exec.Cmd { return exec.Command("pdftotext", path, "-") },
+}
+
+var previewCache = newPreviewCache(64)
+
+// renderPreview returns the preview text/escape-sequences for path, serving
+// from the LRU cache when the file hasn't changed since it was last
+// rendered.
+func renderPreview(path string, settings settingsConfig) string {
+	key, ok := previewCacheKey(path)
+	if ok {
+		if cached, ok := previewCache.get(key); ok {
+			return cached
+		}
+	}
+
+	content := computePreview(path, settings)
+	if ok {
+		previewCache.put(key, content)
+	}
+	return content
+}
+
+// previewCacheKey is (path, mtime, size): cheap to compute, and changes
+// whenever the file's content could plausibly have changed.
+func previewCacheKey(path string) (string, bool) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s|%d|%d", path, fi.ModTime().UnixNano(), fi.Size()), true
+}
+
+func computePreview(path string, settings settingsConfig) string {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err.Error()
+	}
+	if fi.IsDir() {
+		return warning.Render("No preview available")
+	}
+
+	if handler, ok := archiveHandlers[ToLower(filepath.Ext(path))]; ok {
+		out, err := handler(path).Output()
+		if err != nil {
+			return warning.Render("No preview available")
+		}
+		return capBytes(string(out))
+	}
+
+	if HasPrefix(mimeType(path), "image/") {
+		return renderImagePreview(path)
+	}
+
+	return renderTextPreview(path, settings)
+}
+
+// mimeType shells out to `file`, the one tool reliably available to tell a
+// binary format from a text one without us maintaining a signature table.
+// Missing `file` or an unreadable path just falls through to text preview.
+func mimeType(path string) string {
+	out, err := exec.Command("file", "--mime-type", "-b", path).Output()
+	if err != nil {
+		return ""
+	}
+	return TrimSpace(string(out))
+}
+
+// renderTextPreview shells out to the user's configured pager (settings.
+// Previewer, or $LLAMA_PAGER) for syntax-highlighted ANSI output, falling
+// back to a raw, tab-expanded read for plain UTF-8 text.
+func renderTextPreview(path string, settings settingsConfig) string {
+	pager := settings.Previewer
+	if pager == "" {
+		pager = lookup([]string{"LLAMA_PAGER"}, "")
+	}
+	if pager != "" {
+		fields := Fields(pager)
+		cmd := exec.Command(fields[0], append(fields[1:], path)...)
+		if out, err := cmd.CombinedOutput(); err == nil {
+			return capBytes(string(out))
+		}
+		// Pager failed (missing binary, non-zero exit); fall back below.
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err.Error()
+	}
+	defer file.Close()
+	content, _ := io.ReadAll(io.LimitReader(file, previewByteCap))
+
+	if !utf8.Valid(content) {
+		return warning.Render("No preview available")
+	}
+	return Replace(string(content), "\t", "    ", -1)
+}
+
+// renderImagePreview renders inline via whichever terminal graphics
+// protocol the running terminal understands, falling back to chafa's
+// ANSI/Sixel art when none is detected.
+func renderImagePreview(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err.Error()
+	}
+	if len(data) > 5*1024*1024 {
+		return warning.Render("Image too large to preview")
+	}
+
+	graphicsCapable := termenv.ColorProfile() != termenv.Ascii
+	switch {
+	case graphicsCapable && Contains(os.Getenv("TERM"), "kitty"):
+		return kittyEscape(data)
+	case graphicsCapable && os.Getenv("TERM_PROGRAM") == "iTerm.app":
+		return iterm2Escape(data)
+	default:
+		out, err := exec.Command("chafa", path).Output()
+		if err != nil {
+			return warning.Render("No preview available")
+		}
+		return capBytes(string(out))
+	}
+}
+
+func kittyEscape(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	return fmt.Sprintf("\x1b_Gf=100,a=T;%s\x1b\\", encoded)
+}
+
+func iterm2Escape(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d:%s\a", len(data), encoded)
+}
+
+func capBytes(s string) string {
+	if len(s) > previewByteCap {
+		return s[:previewByteCap] + "\n... (truncated)"
+	}
+	return s
+}
+
+// previewLRU is a small LRU of rendered previews. previewCache uses it with
+// (path, mtime, size) keys, so re-hovering over the same, unchanged file is
+// instant instead of re-running a pager or external handler.
+type previewLRU struct {
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type previewCacheItem struct {
+	key   string
+	value string
+}
+
+func newPreviewCache(capacity int) *previewLRU {
+	return &previewLRU{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *previewLRU) get(key string) (string, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*previewCacheItem).value, true
+}
+
+func (c *previewLRU) put(key, value string) {
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		el.Value.(*previewCacheItem).value = value
+		return
+	}
+	el := c.order.PushFront(&previewCacheItem{key: key, value: value})
+	c.items[key] = el
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*previewCacheItem).key)
+		}
+	}
+}
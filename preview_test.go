@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestPreviewLRUGetPut(t *testing.T) {
+	c := newPreviewCache(2)
+	c.put("a", "a-value")
+	if got, ok := c.get("a"); !ok || got != "a-value" {
+		t.Fatalf("get(a) = %q, %v, want a-value, true", got, ok)
+	}
+	if _, ok := c.get("missing"); ok {
+		t.Fatal("get(missing) = true, want false")
+	}
+}
+
+func TestPreviewLRUEvictsOldest(t *testing.T) {
+	c := newPreviewCache(2)
+	c.put("a", "1")
+	c.put("b", "2")
+	c.put("c", "3") // Evicts "a", the least recently used.
+
+	if _, ok := c.get("a"); ok {
+		t.Fatal("a should have been evicted")
+	}
+	if got, ok := c.get("b"); !ok || got != "2" {
+		t.Fatalf("get(b) = %q, %v, want 2, true", got, ok)
+	}
+	if got, ok := c.get("c"); !ok || got != "3" {
+		t.Fatalf("get(c) = %q, %v, want 3, true", got, ok)
+	}
+}
+
+func TestPreviewLRUGetRefreshesRecency(t *testing.T) {
+	c := newPreviewCache(2)
+	c.put("a", "1")
+	c.put("b", "2")
+	c.get("a")      // "a" is now more recently used than "b".
+	c.put("c", "3") // Evicts "b" instead of "a".
+
+	if _, ok := c.get("b"); ok {
+		t.Fatal("b should have been evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("a should still be cached")
+	}
+}
+
+func TestPreviewLRUPutExistingKeyUpdatesValue(t *testing.T) {
+	c := newPreviewCache(2)
+	c.put("a", "1")
+	c.put("a", "2")
+	if got, ok := c.get("a"); !ok || got != "2" {
+		t.Fatalf("get(a) = %q, %v, want 2, true", got, ok)
+	}
+	if c.order.Len() != 1 {
+		t.Fatalf("order.Len() = %d, want 1", c.order.Len())
+	}
+}